@@ -0,0 +1,162 @@
+// Package cache provides a pluggable conditional-GET cache for the
+// client package: an in-memory LRU and an on-disk filesystem
+// implementation, both keyed by endpoint and storing the validators
+// (ETag / Last-Modified) needed for If-None-Match / If-Modified-Since
+// revalidation.
+package cache
+
+import (
+	"container/list"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Entry is a cached response body plus the validators needed to make a
+// conditional GET (If-None-Match / If-Modified-Since) on the next
+// request.
+type Entry struct {
+	Body         []byte    `json:"body"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last_modified,omitempty"`
+	StoredAt     time.Time `json:"stored_at"`
+}
+
+// Cache is implemented by anything that can store client responses
+// keyed by endpoint. Implementations must be safe for concurrent use -
+// the pool package may hit the same cache from many goroutines.
+type Cache interface {
+	Get(key string) (Entry, bool)
+	Set(key string, entry Entry)
+}
+
+// MemoryCache is a fixed-size in-memory LRU cache.
+type MemoryCache struct {
+	mu      sync.Mutex
+	max     int
+	entries map[string]*list.Element
+	order   *list.List // front = most recently used
+}
+
+type memoryCacheItem struct {
+	key   string
+	entry Entry
+}
+
+// NewMemoryCache creates an LRU cache holding at most max entries.
+func NewMemoryCache(max int) *MemoryCache {
+	return &MemoryCache{
+		max:     max,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+func (m *MemoryCache) Get(key string) (Entry, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	el, ok := m.entries[key]
+	if !ok {
+		return Entry{}, false
+	}
+	m.order.MoveToFront(el)
+	return el.Value.(*memoryCacheItem).entry, true
+}
+
+func (m *MemoryCache) Set(key string, entry Entry) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if el, ok := m.entries[key]; ok {
+		el.Value.(*memoryCacheItem).entry = entry
+		m.order.MoveToFront(el)
+		return
+	}
+
+	el := m.order.PushFront(&memoryCacheItem{key: key, entry: entry})
+	m.entries[key] = el
+
+	for m.order.Len() > m.max {
+		oldest := m.order.Back()
+		if oldest == nil {
+			break
+		}
+		m.order.Remove(oldest)
+		delete(m.entries, oldest.Value.(*memoryCacheItem).key)
+	}
+}
+
+// FileCache persists cache entries as one JSON file per key under Dir,
+// so repeated runs of the CLI reuse the same cache without needing a
+// long-lived process.
+type FileCache struct {
+	Dir string
+}
+
+// NewFileCache creates a FileCache rooted at dir, creating dir if it
+// doesn't exist.
+func NewFileCache(dir string) (*FileCache, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("error creating cache dir %s: %v", dir, err)
+	}
+	return &FileCache{Dir: dir}, nil
+}
+
+func (f *FileCache) pathFor(key string) string {
+	sum := sha1.Sum([]byte(key))
+	return filepath.Join(f.Dir, hex.EncodeToString(sum[:])+".json")
+}
+
+func (f *FileCache) Get(key string) (Entry, bool) {
+	body, err := os.ReadFile(f.pathFor(key))
+	if err != nil {
+		return Entry{}, false
+	}
+
+	var entry Entry
+	if err := json.Unmarshal(body, &entry); err != nil {
+		return Entry{}, false
+	}
+	return entry, true
+}
+
+func (f *FileCache) Set(key string, entry Entry) {
+	body, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	_ = os.WriteFile(f.pathFor(key), body, 0644)
+}
+
+// TTLPolicy lets callers tune how long a cached response stays fresh
+// before a conditional GET is needed, split by endpoint type since the
+// programs list changes far less often than an individual program's
+// scopes.
+type TTLPolicy struct {
+	ProgramsTTL time.Duration
+	ScopeTTL    time.Duration
+}
+
+// DefaultTTLPolicy caches the programs list for an hour and
+// per-program scopes/weaknesses for 15 minutes.
+func DefaultTTLPolicy() TTLPolicy {
+	return TTLPolicy{
+		ProgramsTTL: 1 * time.Hour,
+		ScopeTTL:    15 * time.Minute,
+	}
+}
+
+// TTLFor classifies an endpoint into the TTL bucket it belongs to.
+func (p TTLPolicy) TTLFor(endpoint string) time.Duration {
+	if strings.Contains(endpoint, "/structured_scopes") || strings.Contains(endpoint, "/weaknesses") {
+		return p.ScopeTTL
+	}
+	return p.ProgramsTTL
+}
@@ -0,0 +1,88 @@
+package cache
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestMemoryCacheGetSetRoundTrip(t *testing.T) {
+	c := NewMemoryCache(2)
+
+	if _, ok := c.Get("missing"); ok {
+		t.Fatal("Get on empty cache returned ok=true")
+	}
+
+	entry := Entry{Body: []byte("hello"), ETag: `"abc"`, StoredAt: time.Now()}
+	c.Set("k1", entry)
+
+	got, ok := c.Get("k1")
+	if !ok || string(got.Body) != "hello" || got.ETag != `"abc"` {
+		t.Fatalf("Get(k1) = %+v, %v, want the entry just Set", got, ok)
+	}
+}
+
+func TestMemoryCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	c := NewMemoryCache(2)
+
+	c.Set("a", Entry{Body: []byte("a")})
+	c.Set("b", Entry{Body: []byte("b")})
+	c.Get("a") // touch a, making b the least recently used
+	c.Set("c", Entry{Body: []byte("c")})
+
+	if _, ok := c.Get("b"); ok {
+		t.Fatal("b should have been evicted as least-recently-used, but it's still present")
+	}
+	if _, ok := c.Get("a"); !ok {
+		t.Fatal("a was touched after b, so it should still be present")
+	}
+	if _, ok := c.Get("c"); !ok {
+		t.Fatal("c was just inserted, so it should be present")
+	}
+}
+
+func TestMemoryCacheSetOverwritesExisting(t *testing.T) {
+	c := NewMemoryCache(2)
+
+	c.Set("k", Entry{Body: []byte("first")})
+	c.Set("k", Entry{Body: []byte("second")})
+
+	got, ok := c.Get("k")
+	if !ok || string(got.Body) != "second" {
+		t.Fatalf("Get(k) = %+v, %v, want the overwritten entry", got, ok)
+	}
+}
+
+func TestFileCacheGetSetRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	fc, err := NewFileCache(filepath.Join(dir, "sub"))
+	if err != nil {
+		t.Fatalf("NewFileCache: %v", err)
+	}
+
+	if _, ok := fc.Get("/programs"); ok {
+		t.Fatal("Get on empty cache returned ok=true")
+	}
+
+	entry := Entry{Body: []byte(`{"data":[]}`), LastModified: "Mon, 01 Jan 2024 00:00:00 GMT"}
+	fc.Set("/programs", entry)
+
+	got, ok := fc.Get("/programs")
+	if !ok || string(got.Body) != `{"data":[]}` || got.LastModified != entry.LastModified {
+		t.Fatalf("Get(/programs) = %+v, %v, want the entry just Set", got, ok)
+	}
+}
+
+func TestTTLForSplitsByEndpointType(t *testing.T) {
+	p := TTLPolicy{ProgramsTTL: time.Hour, ScopeTTL: 15 * time.Minute}
+
+	if got := p.TTLFor("/programs"); got != time.Hour {
+		t.Fatalf("TTLFor(/programs) = %v, want %v", got, time.Hour)
+	}
+	if got := p.TTLFor("/programs/acme/structured_scopes"); got != 15*time.Minute {
+		t.Fatalf("TTLFor(structured_scopes) = %v, want %v", got, 15*time.Minute)
+	}
+	if got := p.TTLFor("/programs/acme/weaknesses"); got != 15*time.Minute {
+		t.Fatalf("TTLFor(weaknesses) = %v, want %v", got, 15*time.Minute)
+	}
+}
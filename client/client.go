@@ -0,0 +1,516 @@
+// Package client is a HackerOne API client: authenticated requests with
+// shared rate limiting, retry/backoff, and conditional-GET caching, plus
+// typed accessors for programs, structured scopes, and weaknesses.
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/X-ecute/h1module/cache"
+	"github.com/X-ecute/h1module/ratelimit"
+)
+
+// ErrorKind classifies a RequestError so callers can decide whether to
+// retry, re-authenticate, or give up.
+type ErrorKind int
+
+const (
+	// ErrKindTransport covers network-level failures (DNS, connection
+	// reset, timeouts) where the request never got a response.
+	ErrKindTransport ErrorKind = iota
+	// ErrKindAuth covers 401/403 responses - retrying won't help.
+	ErrKindAuth
+	// ErrKindRateLimit covers 429 (and 503, treated the same way).
+	ErrKindRateLimit
+	// ErrKindServer covers other 5xx responses.
+	ErrKindServer
+	// ErrKindHTTP covers any other non-200 status.
+	ErrKindHTTP
+)
+
+// RequestError is returned by makeRequest on failure, with enough detail
+// for a caller to distinguish "my token is bad" from "back off and retry".
+type RequestError struct {
+	Kind       ErrorKind
+	StatusCode int
+	RetryAfter time.Duration
+	Endpoint   string
+	Err        error
+}
+
+func (e *RequestError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", e.Endpoint, e.Err)
+	}
+	return fmt.Sprintf("%s: request failed with status %d", e.Endpoint, e.StatusCode)
+}
+
+func (e *RequestError) Unwrap() error { return e.Err }
+
+// H1Client represents the HackerOne API client
+type H1Client struct {
+	Username       string
+	APIToken       string
+	BaseURL        string
+	RateLimitDelay time.Duration
+
+	httpClient  *http.Client
+	limiter     *ratelimit.Limiter
+	retryPolicy ratelimit.RetryPolicy
+	cache       cache.Cache
+	cacheTTL    cache.TTLPolicy
+}
+
+// Options tunes the rate limiter and retry behavior of an H1Client.
+// The zero value is not usable directly; start from DefaultOptions and
+// override what you need.
+type Options struct {
+	// QPS is the sustained request rate, in requests per second.
+	QPS float64
+	// Burst is the maximum number of requests that can fire back to
+	// back before the limiter starts spacing them out.
+	Burst int
+	// Retry controls backoff on 429/503/5xx responses.
+	Retry ratelimit.RetryPolicy
+	// Cache, if set, enables conditional GET caching of responses (see
+	// the cache package). Nil disables caching entirely.
+	Cache cache.Cache
+	// CacheTTL controls how long a cached response is served without
+	// even a conditional GET. Ignored if Cache is nil.
+	CacheTTL cache.TTLPolicy
+}
+
+// DefaultOptions targets HackerOne's 600 requests/minute limit (10
+// req/s) with a small burst allowance, and the package's default retry
+// policy.
+func DefaultOptions() Options {
+	return Options{
+		QPS:      10,
+		Burst:    5,
+		Retry:    ratelimit.DefaultRetryPolicy,
+		CacheTTL: cache.DefaultTTLPolicy(),
+	}
+}
+
+// Program represents a HackerOne program
+type Program struct {
+	ID         string `json:"id"`
+	Type       string `json:"type"`
+	Attributes struct {
+		Handle         string `json:"handle"`
+		Name           string `json:"name"`
+		Currency       string `json:"currency"`
+		Policy         string `json:"policy"`
+		State          string `json:"state"`
+		OffersBounties bool   `json:"offers_bounties"`
+		OpenScope      bool   `json:"open_scope"`
+	} `json:"attributes"`
+}
+
+// ProgramsResponse represents the response from getting all programs
+type ProgramsResponse struct {
+	Data  []Program         `json:"data"`
+	Links map[string]string `json:"links"`
+}
+
+// StructuredScope represents a scope entry in a program
+type StructuredScope struct {
+	ID         string `json:"id"`
+	Type       string `json:"type"`
+	Attributes struct {
+		AssetType             string `json:"asset_type"`
+		AssetIdentifier       string `json:"asset_identifier"`
+		EligibleForBounty     bool   `json:"eligible_for_bounty"`
+		EligibleForSubmission bool   `json:"eligible_for_submission"`
+		Instruction           string `json:"instruction"`
+		MaxSeverity           string `json:"max_severity"`
+	} `json:"attributes"`
+}
+
+// StructuredScopesResponse represents the response from getting structured scopes
+type StructuredScopesResponse struct {
+	Data  []StructuredScope `json:"data"`
+	Links map[string]string `json:"links"`
+}
+
+// Weakness represents a weakness/CWE entry
+type Weakness struct {
+	ID         string `json:"id"`
+	Type       string `json:"type"`
+	Attributes struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+		ExternalID  string `json:"external_id"`
+	} `json:"attributes"`
+}
+
+// WeaknessesResponse represents the response from getting weaknesses
+type WeaknessesResponse struct {
+	Data  []Weakness        `json:"data"`
+	Links map[string]string `json:"links"`
+}
+
+// New creates a new HackerOne API client using DefaultOptions.
+func New(username, token string) *H1Client {
+	return NewWithOptions(username, token, DefaultOptions())
+}
+
+// NewWithOptions creates a new HackerOne API client with a
+// caller-tuned rate limiter and retry policy.
+func NewWithOptions(username, token string, opts Options) *H1Client {
+	return &H1Client{
+		Username:       username,
+		APIToken:       token,
+		BaseURL:        "https://api.hackerone.com/v1/hackers",
+		RateLimitDelay: 100 * time.Millisecond, // kept for callers that still pace pagination manually
+		httpClient:     &http.Client{},
+		limiter:        ratelimit.NewLimiter(opts.QPS, opts.Burst),
+		retryPolicy:    opts.Retry,
+		cache:          opts.Cache,
+		cacheTTL:       opts.CacheTTL,
+	}
+}
+
+// makeRequest makes an authenticated request to the HackerOne API. It
+// blocks on the client's shared rate limiter, and retries 429/503/5xx
+// responses with capped exponential backoff (honoring Retry-After when
+// present). Failures are returned as *RequestError so callers can tell
+// a bad token apart from a transient rate limit. ctx governs both the
+// rate-limiter wait and the retry loop.
+//
+// A request that's served entirely from the cache (fresh, within its
+// TTL) never hits the network and so never draws from the rate
+// limiter. Once the cache is stale, though, the request still goes out
+// - as a conditional GET that may come back 200 with a full body, not
+// just a cheap 304 - so it must still wait on the limiter like any
+// other request; only the one true cache hit above is exempt.
+func (c *H1Client) makeRequest(ctx context.Context, method, endpoint string) ([]byte, error) {
+	url := c.BaseURL + endpoint
+
+	var cached cache.Entry
+	var haveCached bool
+	if c.cache != nil && method == http.MethodGet {
+		cached, haveCached = c.cache.Get(endpoint)
+		if haveCached && time.Since(cached.StoredAt) < c.cacheTTL.TTLFor(endpoint) {
+			return cached.Body, nil
+		}
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= c.retryPolicy.MaxAttempts; attempt++ {
+		if err := c.limiter.Wait(ctx); err != nil {
+			return nil, &RequestError{Kind: ErrKindTransport, Endpoint: endpoint, Err: err}
+		}
+
+		req, err := http.NewRequestWithContext(ctx, method, url, nil)
+		if err != nil {
+			return nil, &RequestError{Kind: ErrKindTransport, Endpoint: endpoint, Err: err}
+		}
+		req.Header.Set("Accept", "application/json")
+		req.SetBasicAuth(c.Username, c.APIToken)
+		if haveCached {
+			if cached.ETag != "" {
+				req.Header.Set("If-None-Match", cached.ETag)
+			}
+			if cached.LastModified != "" {
+				req.Header.Set("If-Modified-Since", cached.LastModified)
+			}
+		}
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			lastErr = &RequestError{Kind: ErrKindTransport, Endpoint: endpoint, Err: err}
+			if ctx.Err() != nil {
+				return nil, lastErr
+			}
+			if !c.sleepForRetry(ctx, attempt, 0) {
+				return nil, lastErr
+			}
+			continue
+		}
+
+		body, readErr := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		switch {
+		case resp.StatusCode == http.StatusNotModified:
+			cached.StoredAt = time.Now()
+			if c.cache != nil {
+				c.cache.Set(endpoint, cached)
+			}
+			return cached.Body, nil
+
+		case resp.StatusCode == http.StatusOK:
+			if readErr != nil {
+				return nil, &RequestError{Kind: ErrKindTransport, Endpoint: endpoint, Err: readErr}
+			}
+			if c.cache != nil && method == http.MethodGet {
+				c.cache.Set(endpoint, cache.Entry{
+					Body:         body,
+					ETag:         resp.Header.Get("ETag"),
+					LastModified: resp.Header.Get("Last-Modified"),
+					StoredAt:     time.Now(),
+				})
+			}
+			return body, nil
+
+		case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+			return nil, &RequestError{Kind: ErrKindAuth, StatusCode: resp.StatusCode, Endpoint: endpoint,
+				Err: fmt.Errorf("API request failed with status: %s", resp.Status)}
+
+		case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable:
+			retryAfter := ratelimit.ParseRetryAfter(resp.Header.Get("Retry-After"))
+			lastErr = &RequestError{Kind: ErrKindRateLimit, StatusCode: resp.StatusCode, RetryAfter: retryAfter, Endpoint: endpoint,
+				Err: fmt.Errorf("API request failed with status: %s", resp.Status)}
+			if !c.sleepForRetry(ctx, attempt, retryAfter) {
+				return nil, lastErr
+			}
+
+		case resp.StatusCode >= 500:
+			lastErr = &RequestError{Kind: ErrKindServer, StatusCode: resp.StatusCode, Endpoint: endpoint,
+				Err: fmt.Errorf("API request failed with status: %s", resp.Status)}
+			if !c.sleepForRetry(ctx, attempt, 0) {
+				return nil, lastErr
+			}
+
+		default:
+			return nil, &RequestError{Kind: ErrKindHTTP, StatusCode: resp.StatusCode, Endpoint: endpoint,
+				Err: fmt.Errorf("API request failed with status: %s", resp.Status)}
+		}
+	}
+
+	return nil, lastErr
+}
+
+// sleepForRetry waits before the next retry attempt and reports whether
+// another attempt remains. It prefers the server's Retry-After when
+// given, otherwise falls back to the retry policy's backoff curve.
+// Returns false (without sleeping) once attempts are exhausted or ctx
+// is cancelled.
+func (c *H1Client) sleepForRetry(ctx context.Context, attempt int, retryAfter time.Duration) bool {
+	if attempt >= c.retryPolicy.MaxAttempts {
+		return false
+	}
+
+	delay := retryAfter
+	if delay == 0 {
+		delay = c.retryPolicy.Backoff(attempt)
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// GetAllProgramsPaginated gets all programs with pagination support
+func (c *H1Client) GetAllProgramsPaginated(ctx context.Context) ([]Program, error) {
+	var allPrograms []Program
+	nextURL := "/programs"
+	page := 1
+
+	for nextURL != "" {
+		fmt.Printf("Fetching page %d...\n", page)
+
+		response, next, err := c.GetProgramsPage(ctx, nextURL)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching page %d: %v", page, err)
+		}
+
+		allPrograms = append(allPrograms, response.Data...)
+		fmt.Printf("Page %d: fetched %d programs (total: %d)\n", page, len(response.Data), len(allPrograms))
+
+		nextURL = next
+		if nextURL != "" {
+			page++
+		}
+	}
+
+	return allPrograms, nil
+}
+
+// GetProgramsPage fetches a single page of the programs list from
+// endpoint (typically "/programs" or a "next" link from a previous
+// page) and returns the decoded response along with the endpoint for
+// the next page, or "" if this was the last page. It exists alongside
+// GetAllProgramsPaginated so callers that need to checkpoint between
+// pages (see the store package) can drive the pagination loop
+// themselves.
+func (c *H1Client) GetProgramsPage(ctx context.Context, endpoint string) (*ProgramsResponse, string, error) {
+	body, err := c.makeRequest(ctx, "GET", endpoint)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var response ProgramsResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, "", err
+	}
+
+	next := ""
+	if nextLink, exists := response.Links["next"]; exists && nextLink != "" {
+		next = extractEndpoint(nextLink)
+	}
+	return &response, next, nil
+}
+
+// extractEndpoint extracts the API endpoint from a full URL
+func extractEndpoint(fullURL string) string {
+	// Remove the base URL part to get just the endpoint
+	baseURL := "https://api.hackerone.com/v1/hackers"
+	if strings.HasPrefix(fullURL, baseURL) {
+		return strings.TrimPrefix(fullURL, baseURL)
+	}
+	return fullURL
+}
+
+// SaveProgramsToFile saves programs to a JSON file
+func SaveProgramsToFile(programs []Program, filename string) error {
+	file, err := json.MarshalIndent(programs, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	err = ioutil.WriteFile(filename, file, 0644)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Successfully saved %d programs to %s\n", len(programs), filename)
+	return nil
+}
+
+// GetStructuredScopes gets the structured scopes for a program (single page, legacy).
+func (c *H1Client) GetStructuredScopes(ctx context.Context, programHandle string) (*StructuredScopesResponse, error) {
+	endpoint := fmt.Sprintf("/programs/%s/structured_scopes", programHandle)
+	body, err := c.makeRequest(ctx, "GET", endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	var response StructuredScopesResponse
+	err = json.Unmarshal(body, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+// GetStructuredScopesPaginated gets every structured scope page for a
+// program, following "next" links the same way GetAllProgramsPaginated
+// does for the programs list.
+func (c *H1Client) GetStructuredScopesPaginated(ctx context.Context, programHandle string) ([]StructuredScope, error) {
+	var all []StructuredScope
+	nextURL := fmt.Sprintf("/programs/%s/structured_scopes", programHandle)
+
+	for nextURL != "" {
+		body, err := c.makeRequest(ctx, "GET", nextURL)
+		if err != nil {
+			return nil, err
+		}
+
+		var response StructuredScopesResponse
+		if err := json.Unmarshal(body, &response); err != nil {
+			return nil, err
+		}
+		all = append(all, response.Data...)
+
+		nextURL = ""
+		if nextLink, exists := response.Links["next"]; exists && nextLink != "" {
+			nextURL = extractEndpoint(nextLink)
+		}
+	}
+
+	return all, nil
+}
+
+// GetWeaknesses gets the weaknesses for a program (single page, legacy).
+func (c *H1Client) GetWeaknesses(ctx context.Context, programHandle string) (*WeaknessesResponse, error) {
+	endpoint := fmt.Sprintf("/programs/%s/weaknesses", programHandle)
+	body, err := c.makeRequest(ctx, "GET", endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	var response WeaknessesResponse
+	err = json.Unmarshal(body, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+// GetWeaknessesPaginated gets every weakness page for a program,
+// following "next" links the same way GetAllProgramsPaginated does for
+// the programs list.
+func (c *H1Client) GetWeaknessesPaginated(ctx context.Context, programHandle string) ([]Weakness, error) {
+	var all []Weakness
+	nextURL := fmt.Sprintf("/programs/%s/weaknesses", programHandle)
+
+	for nextURL != "" {
+		body, err := c.makeRequest(ctx, "GET", nextURL)
+		if err != nil {
+			return nil, err
+		}
+
+		var response WeaknessesResponse
+		if err := json.Unmarshal(body, &response); err != nil {
+			return nil, err
+		}
+		all = append(all, response.Data...)
+
+		nextURL = ""
+		if nextLink, exists := response.Links["next"]; exists && nextLink != "" {
+			nextURL = extractEndpoint(nextLink)
+		}
+	}
+
+	return all, nil
+}
+
+// GetAllPrograms gets all programs (single page - legacy).
+func (c *H1Client) GetAllPrograms(ctx context.Context) (*ProgramsResponse, error) {
+	body, err := c.makeRequest(ctx, "GET", "/programs")
+	if err != nil {
+		return nil, err
+	}
+
+	var response ProgramsResponse
+	err = json.Unmarshal(body, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+// GetProgram gets a specific program's details.
+func (c *H1Client) GetProgram(ctx context.Context, programHandle string) (*Program, error) {
+	endpoint := fmt.Sprintf("/programs/%s", programHandle)
+	body, err := c.makeRequest(ctx, "GET", endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		Data Program `json:"data"`
+	}
+	err = json.Unmarshal(body, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.Data, nil
+}
@@ -0,0 +1,58 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/X-ecute/h1module/cache"
+	"github.com/X-ecute/h1module/ratelimit"
+)
+
+// TestMakeRequestRateLimitsStaleRevalidation guards against the limiter
+// being skipped for every request behind a cache, not just true cache
+// hits: once a cached entry goes stale, the conditional GET that
+// follows still hits the network (and may come back 200, not 304), so
+// it must still wait on the token bucket.
+func TestMakeRequestRateLimitsStaleRevalidation(t *testing.T) {
+	var hits int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.Write([]byte(`{"data":[]}`))
+	}))
+	defer server.Close()
+
+	c := NewWithOptions("user", "token", Options{
+		QPS:      1,
+		Burst:    1,
+		Retry:    ratelimit.DefaultRetryPolicy,
+		Cache:    cache.NewMemoryCache(10),
+		CacheTTL: cache.TTLPolicy{ProgramsTTL: time.Millisecond, ScopeTTL: time.Millisecond},
+	})
+	c.BaseURL = server.URL
+
+	if _, err := c.makeRequest(context.Background(), http.MethodGet, "/programs"); err != nil {
+		t.Fatalf("first request: %v", err)
+	}
+
+	time.Sleep(5 * time.Millisecond) // let the cached entry go stale
+
+	start := time.Now()
+	if _, err := c.makeRequest(context.Background(), http.MethodGet, "/programs"); err != nil {
+		t.Fatalf("second request: %v", err)
+	}
+	elapsed := time.Since(start)
+
+	// Burst is exhausted after the first request, so with QPS=1 the
+	// second request must wait roughly a second for a token to refill.
+	// A buggy implementation that skips the limiter for any cached
+	// endpoint would return near-instantly here.
+	if elapsed < 400*time.Millisecond {
+		t.Fatalf("second request returned after %v, want it to have waited on the rate limiter (stale cache entries still hit the network)", elapsed)
+	}
+	if hits != 2 {
+		t.Fatalf("server saw %d requests, want 2 (cache must not have served the stale entry without revalidating)", hits)
+	}
+}
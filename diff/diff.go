@@ -0,0 +1,193 @@
+// Package diff computes and reports the differences between two saved
+// program+scope snapshots (as produced by "enrich-all"/"scopes-all" or
+// a completed crawl): added/removed/modified scope entries, keyed by
+// (program handle, asset identifier, asset type). This is the one
+// snapshot-diffing implementation in the codebase - the "crawl"
+// subcommand uses it too for its --incremental changelog over the store
+// package's checkpoints, rather than maintaining a second, incompatible
+// diff of its own.
+package diff
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/X-ecute/h1module/client"
+	"github.com/X-ecute/h1module/pool"
+)
+
+// Entry is a single added/removed/modified scope entry between two
+// saved program+scope snapshots, keyed by (program handle, asset
+// identifier, asset type).
+type Entry struct {
+	ProgramHandle   string `json:"program_handle"`
+	AssetIdentifier string `json:"asset_identifier"`
+	AssetType       string `json:"asset_type"`
+	Change          string `json:"change"` // "added", "removed", or "modified"
+	Details         string `json:"details,omitempty"`
+}
+
+type scopeKey struct {
+	handle string
+	id     string
+	typ    string
+}
+
+func scopeKeyFor(handle string, s pool.Detail, idx int) scopeKey {
+	sc := s.Scopes[idx]
+	return scopeKey{handle: handle, id: sc.Attributes.AssetIdentifier, typ: sc.Attributes.AssetType}
+}
+
+// ScopeDiff compares two program+scope snapshots (as saved by
+// enrich-all/scopes-all, or a completed crawl) and returns every scope
+// that was added, removed, or changed state - bounty eligibility,
+// submission eligibility, max severity, or the owning program's
+// visibility (private/public).
+func ScopeDiff(previous, current []pool.Detail) []Entry {
+	prevScopes := make(map[scopeKey]client.StructuredScope)
+	prevState := make(map[string]string)
+	for _, d := range previous {
+		prevState[d.Program.Attributes.Handle] = d.Program.Attributes.State
+		for i, s := range d.Scopes {
+			prevScopes[scopeKeyFor(d.Program.Attributes.Handle, d, i)] = s
+		}
+	}
+
+	curScopes := make(map[scopeKey]client.StructuredScope)
+	curState := make(map[string]string)
+	for _, d := range current {
+		curState[d.Program.Attributes.Handle] = d.Program.Attributes.State
+		for i, s := range d.Scopes {
+			curScopes[scopeKeyFor(d.Program.Attributes.Handle, d, i)] = s
+		}
+	}
+
+	var entries []Entry
+
+	for key, cur := range curScopes {
+		prev, existed := prevScopes[key]
+		if !existed {
+			entries = append(entries, Entry{
+				ProgramHandle: key.handle, AssetIdentifier: key.id, AssetType: key.typ, Change: "added",
+			})
+			continue
+		}
+
+		var changes []string
+		if !prev.Attributes.EligibleForBounty && cur.Attributes.EligibleForBounty {
+			changes = append(changes, "became bounty-eligible")
+		} else if prev.Attributes.EligibleForBounty && !cur.Attributes.EligibleForBounty {
+			changes = append(changes, "no longer bounty-eligible")
+		}
+		if prev.Attributes.MaxSeverity != cur.Attributes.MaxSeverity {
+			changes = append(changes, fmt.Sprintf("max_severity %s -> %s", prev.Attributes.MaxSeverity, cur.Attributes.MaxSeverity))
+		}
+		if prev.Attributes.EligibleForSubmission != cur.Attributes.EligibleForSubmission {
+			changes = append(changes, fmt.Sprintf("eligible_for_submission %t -> %t", prev.Attributes.EligibleForSubmission, cur.Attributes.EligibleForSubmission))
+		}
+		if prevS, curS := prevState[key.handle], curState[key.handle]; prevS != curS {
+			changes = append(changes, fmt.Sprintf("program state %s -> %s", prevS, curS))
+		}
+
+		if len(changes) > 0 {
+			entries = append(entries, Entry{
+				ProgramHandle: key.handle, AssetIdentifier: key.id, AssetType: key.typ, Change: "modified",
+				Details: strings.Join(changes, "; "),
+			})
+		}
+	}
+
+	for key := range prevScopes {
+		if _, stillThere := curScopes[key]; !stillThere {
+			entries = append(entries, Entry{
+				ProgramHandle: key.handle, AssetIdentifier: key.id, AssetType: key.typ, Change: "removed",
+			})
+		}
+	}
+
+	return entries
+}
+
+// LoadSnapshot reads a []pool.Detail JSON file, as written by
+// "enrich-all" or "scopes-all --format json".
+func LoadSnapshot(path string) ([]pool.Detail, error) {
+	body, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading snapshot %s: %v", path, err)
+	}
+	var details []pool.Detail
+	if err := json.Unmarshal(body, &details); err != nil {
+		return nil, fmt.Errorf("error parsing snapshot %s: %v", path, err)
+	}
+	return details, nil
+}
+
+// PrintScopeDiff renders a diff in the human-readable format used by
+// the "diff" CLI subcommand.
+func PrintScopeDiff(entries []Entry) {
+	if len(entries) == 0 {
+		fmt.Println("No scope changes detected.")
+		return
+	}
+
+	fmt.Printf("\n=== Scope Diff (%d changes) ===\n\n", len(entries))
+	for _, e := range entries {
+		switch e.Change {
+		case "added":
+			fmt.Printf("+ %s: %s (%s)\n", e.ProgramHandle, e.AssetIdentifier, e.AssetType)
+		case "removed":
+			fmt.Printf("- %s: %s (%s)\n", e.ProgramHandle, e.AssetIdentifier, e.AssetType)
+		case "modified":
+			fmt.Printf("~ %s: %s (%s) - %s\n", e.ProgramHandle, e.AssetIdentifier, e.AssetType, e.Details)
+		}
+	}
+}
+
+// slackWebhookPayload is the minimal shape Slack's "Incoming Webhook"
+// integration expects.
+type slackWebhookPayload struct {
+	Text string `json:"text"`
+}
+
+// NotifyWebhook posts a compact summary of entries to a Slack-style
+// incoming webhook URL, so a cron job can alert on scope expansion
+// without a human watching the CLI output.
+func NotifyWebhook(webhookURL string, entries []Entry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	var lines []string
+	for _, e := range entries {
+		switch e.Change {
+		case "added":
+			lines = append(lines, fmt.Sprintf("+ %s: %s (%s)", e.ProgramHandle, e.AssetIdentifier, e.AssetType))
+		case "removed":
+			lines = append(lines, fmt.Sprintf("- %s: %s (%s)", e.ProgramHandle, e.AssetIdentifier, e.AssetType))
+		case "modified":
+			lines = append(lines, fmt.Sprintf("~ %s: %s (%s) - %s", e.ProgramHandle, e.AssetIdentifier, e.AssetType, e.Details))
+		}
+	}
+
+	payload, err := json.Marshal(slackWebhookPayload{
+		Text: fmt.Sprintf("h1module scope diff: %d changes\n%s", len(entries), strings.Join(lines, "\n")),
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.Post(webhookURL, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("error posting to webhook: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status: %s", resp.Status)
+	}
+	return nil
+}
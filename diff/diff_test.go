@@ -0,0 +1,109 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/X-ecute/h1module/client"
+	"github.com/X-ecute/h1module/pool"
+)
+
+func detail(handle, state string, scopes ...client.StructuredScope) pool.Detail {
+	var p client.Program
+	p.Attributes.Handle = handle
+	p.Attributes.State = state
+	return pool.Detail{Program: p, Scopes: scopes}
+}
+
+func scope(identifier, assetType string, bounty, submission bool, severity string) client.StructuredScope {
+	var s client.StructuredScope
+	s.Attributes.AssetIdentifier = identifier
+	s.Attributes.AssetType = assetType
+	s.Attributes.EligibleForBounty = bounty
+	s.Attributes.EligibleForSubmission = submission
+	s.Attributes.MaxSeverity = severity
+	return s
+}
+
+func TestScopeDiffDetectsAdded(t *testing.T) {
+	previous := []pool.Detail{detail("acme", "public")}
+	current := []pool.Detail{detail("acme", "public", scope("api.acme.com", "URL", true, true, "high"))}
+
+	entries := ScopeDiff(previous, current)
+	if len(entries) != 1 || entries[0].Change != "added" || entries[0].AssetIdentifier != "api.acme.com" {
+		t.Fatalf("got %+v, want a single 'added' entry for api.acme.com", entries)
+	}
+}
+
+func TestScopeDiffDetectsRemoved(t *testing.T) {
+	previous := []pool.Detail{detail("acme", "public", scope("api.acme.com", "URL", true, true, "high"))}
+	current := []pool.Detail{detail("acme", "public")}
+
+	entries := ScopeDiff(previous, current)
+	if len(entries) != 1 || entries[0].Change != "removed" || entries[0].AssetIdentifier != "api.acme.com" {
+		t.Fatalf("got %+v, want a single 'removed' entry for api.acme.com", entries)
+	}
+}
+
+func TestScopeDiffDetectsBountyEligibilityChange(t *testing.T) {
+	previous := []pool.Detail{detail("acme", "public", scope("api.acme.com", "URL", false, true, "high"))}
+	current := []pool.Detail{detail("acme", "public", scope("api.acme.com", "URL", true, true, "high"))}
+
+	entries := ScopeDiff(previous, current)
+	if len(entries) != 1 || entries[0].Change != "modified" {
+		t.Fatalf("got %+v, want a single 'modified' entry", entries)
+	}
+	if entries[0].Details != "became bounty-eligible" {
+		t.Fatalf("Details = %q, want %q", entries[0].Details, "became bounty-eligible")
+	}
+}
+
+func TestScopeDiffDetectsMaxSeverityChange(t *testing.T) {
+	previous := []pool.Detail{detail("acme", "public", scope("api.acme.com", "URL", true, true, "medium"))}
+	current := []pool.Detail{detail("acme", "public", scope("api.acme.com", "URL", true, true, "critical"))}
+
+	entries := ScopeDiff(previous, current)
+	if len(entries) != 1 || entries[0].Change != "modified" {
+		t.Fatalf("got %+v, want a single 'modified' entry", entries)
+	}
+	if entries[0].Details != "max_severity medium -> critical" {
+		t.Fatalf("Details = %q, want max_severity medium -> critical", entries[0].Details)
+	}
+}
+
+func TestScopeDiffDetectsProgramStateChange(t *testing.T) {
+	previous := []pool.Detail{detail("acme", "private", scope("api.acme.com", "URL", true, true, "high"))}
+	current := []pool.Detail{detail("acme", "public", scope("api.acme.com", "URL", true, true, "high"))}
+
+	entries := ScopeDiff(previous, current)
+	if len(entries) != 1 || entries[0].Change != "modified" {
+		t.Fatalf("got %+v, want a single 'modified' entry", entries)
+	}
+	if entries[0].Details != "program state private -> public" {
+		t.Fatalf("Details = %q, want program state private -> public", entries[0].Details)
+	}
+}
+
+func TestScopeDiffNoChanges(t *testing.T) {
+	s := scope("api.acme.com", "URL", true, true, "high")
+	previous := []pool.Detail{detail("acme", "public", s)}
+	current := []pool.Detail{detail("acme", "public", s)}
+
+	if entries := ScopeDiff(previous, current); len(entries) != 0 {
+		t.Fatalf("got %+v, want no entries for identical snapshots", entries)
+	}
+}
+
+func TestScopeDiffKeysByAssetTypeToo(t *testing.T) {
+	// Same identifier, different asset_type should not be treated as the same scope.
+	previous := []pool.Detail{detail("acme", "public", scope("10.0.0.1", "CIDR", true, true, "high"))}
+	current := []pool.Detail{detail("acme", "public", scope("10.0.0.1", "IP_ADDRESS", true, true, "high"))}
+
+	entries := ScopeDiff(previous, current)
+	changes := map[string]int{}
+	for _, e := range entries {
+		changes[e.Change]++
+	}
+	if changes["added"] != 1 || changes["removed"] != 1 {
+		t.Fatalf("got %+v, want one 'added' and one 'removed' entry for the asset-type change", entries)
+	}
+}
@@ -1,259 +1,26 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"log"
-	"net/http"
 	"os"
+	"strconv"
 	"strings"
 	"time"
-)
-
-// H1Client represents the HackerOne API client
-type H1Client struct {
-	Username      string
-	APIToken      string
-	BaseURL       string
-	RateLimitDelay time.Duration
-}
-
-// Program represents a HackerOne program
-type Program struct {
-	ID         string `json:"id"`
-	Type       string `json:"type"`
-	Attributes struct {
-		Handle         string `json:"handle"`
-		Name           string `json:"name"`
-		Currency       string `json:"currency"`
-		Policy         string `json:"policy"`
-		State          string `json:"state"`
-		OffersBounties bool   `json:"offers_bounties"`
-		OpenScope      bool   `json:"open_scope"`
-	} `json:"attributes"`
-}
-
-// ProgramsResponse represents the response from getting all programs
-type ProgramsResponse struct {
-	Data  []Program         `json:"data"`
-	Links map[string]string `json:"links"`
-}
-
-// StructuredScope represents a scope entry in a program
-type StructuredScope struct {
-	ID         string `json:"id"`
-	Type       string `json:"type"`
-	Attributes struct {
-		AssetType             string `json:"asset_type"`
-		AssetIdentifier       string `json:"asset_identifier"`
-		EligibleForBounty     bool   `json:"eligible_for_bounty"`
-		EligibleForSubmission bool   `json:"eligible_for_submission"`
-		Instruction           string `json:"instruction"`
-		MaxSeverity           string `json:"max_severity"`
-	} `json:"attributes"`
-}
-
-// StructuredScopesResponse represents the response from getting structured scopes
-type StructuredScopesResponse struct {
-	Data  []StructuredScope `json:"data"`
-	Links map[string]string `json:"links"`
-}
-
-// Weakness represents a weakness/CWE entry
-type Weakness struct {
-	ID         string `json:"id"`
-	Type       string `json:"type"`
-	Attributes struct {
-		Name        string `json:"name"`
-		Description string `json:"description"`
-		ExternalID  string `json:"external_id"`
-	} `json:"attributes"`
-}
-
-// WeaknessesResponse represents the response from getting weaknesses
-type WeaknessesResponse struct {
-	Data  []Weakness        `json:"data"`
-	Links map[string]string `json:"links"`
-}
-
-// NewH1Client creates a new HackerOne API client
-func NewH1Client(username, token string) *H1Client {
-	return &H1Client{
-		Username:      username,
-		APIToken:      token,
-		BaseURL:       "https://api.hackerone.com/v1/hackers",
-		RateLimitDelay: 100 * time.Millisecond, // 600 requests per minute = ~100ms between requests
-	}
-}
-
-// makeRequest makes an authenticated request to the HackerOne API
-func (c *H1Client) makeRequest(method, endpoint string) ([]byte, error) {
-	url := c.BaseURL + endpoint
-
-	req, err := http.NewRequest(method, url, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	req.Header.Set("Accept", "application/json")
-	req.SetBasicAuth(c.Username, c.APIToken)
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API request failed with status: %s", resp.Status)
-	}
-
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-
-	return body, nil
-}
-
-// GetAllProgramsPaginated gets all programs with pagination support
-func (c *H1Client) GetAllProgramsPaginated() ([]Program, error) {
-	var allPrograms []Program
-	nextURL := "/programs"
-	page := 1
-
-	for nextURL != "" {
-		fmt.Printf("Fetching page %d...\n", page)
-
-		body, err := c.makeRequest("GET", nextURL)
-		if err != nil {
-			return nil, fmt.Errorf("error fetching page %d: %v", page, err)
-		}
-
-		var response ProgramsResponse
-		err = json.Unmarshal(body, &response)
-		if err != nil {
-			return nil, fmt.Errorf("error parsing page %d: %v", page, err)
-		}
-
-		allPrograms = append(allPrograms, response.Data...)
-		fmt.Printf("Page %d: fetched %d programs (total: %d)\n", page, len(response.Data), len(allPrograms))
-
-		// Check for next page
-		if nextLink, exists := response.Links["next"]; exists && nextLink != "" {
-			// Extract just the endpoint part from the full URL
-			nextURL = extractEndpoint(nextLink)
-			page++
 
-			// Respect rate limit
-			time.Sleep(c.RateLimitDelay)
-		} else {
-			nextURL = ""
-		}
-	}
-
-	return allPrograms, nil
-}
-
-// extractEndpoint extracts the API endpoint from a full URL
-func extractEndpoint(fullURL string) string {
-	// Remove the base URL part to get just the endpoint
-	baseURL := "https://api.hackerone.com/v1/hackers"
-	if strings.HasPrefix(fullURL, baseURL) {
-		return strings.TrimPrefix(fullURL, baseURL)
-	}
-	return fullURL
-}
-
-// SaveProgramsToFile saves programs to a JSON file
-func SaveProgramsToFile(programs []Program, filename string) error {
-	file, err := json.MarshalIndent(programs, "", "  ")
-	if err != nil {
-		return err
-	}
-
-	err = ioutil.WriteFile(filename, file, 0644)
-	if err != nil {
-		return err
-	}
-
-	fmt.Printf("Successfully saved %d programs to %s\n", len(programs), filename)
-	return nil
-}
-
-// Mode 1: Get Structured Scopes for a program
-func (c *H1Client) GetStructuredScopes(programHandle string) (*StructuredScopesResponse, error) {
-	endpoint := fmt.Sprintf("/programs/%s/structured_scopes", programHandle)
-	body, err := c.makeRequest("GET", endpoint)
-	if err != nil {
-		return nil, err
-	}
-
-	var response StructuredScopesResponse
-	err = json.Unmarshal(body, &response)
-	if err != nil {
-		return nil, err
-	}
-
-	return &response, nil
-}
-
-// Mode 2: Get Weaknesses for a program
-func (c *H1Client) GetWeaknesses(programHandle string) (*WeaknessesResponse, error) {
-	endpoint := fmt.Sprintf("/programs/%s/weaknesses", programHandle)
-	body, err := c.makeRequest("GET", endpoint)
-	if err != nil {
-		return nil, err
-	}
-
-	var response WeaknessesResponse
-	err = json.Unmarshal(body, &response)
-	if err != nil {
-		return nil, err
-	}
-
-	return &response, nil
-}
-
-// Mode 3: Get All Programs (single page - legacy)
-func (c *H1Client) GetAllPrograms() (*ProgramsResponse, error) {
-	body, err := c.makeRequest("GET", "/programs")
-	if err != nil {
-		return nil, err
-	}
-
-	var response ProgramsResponse
-	err = json.Unmarshal(body, &response)
-	if err != nil {
-		return nil, err
-	}
-
-	return &response, nil
-}
-
-// Mode 4: Get Specific Program
-func (c *H1Client) GetProgram(programHandle string) (*Program, error) {
-	endpoint := fmt.Sprintf("/programs/%s", programHandle)
-	body, err := c.makeRequest("GET", endpoint)
-	if err != nil {
-		return nil, err
-	}
-
-	var response struct {
-		Data Program `json:"data"`
-	}
-	err = json.Unmarshal(body, &response)
-	if err != nil {
-		return nil, err
-	}
-
-	return &response.Data, nil
-}
+	"github.com/X-ecute/h1module/cache"
+	"github.com/X-ecute/h1module/client"
+	"github.com/X-ecute/h1module/diff"
+	"github.com/X-ecute/h1module/output"
+	"github.com/X-ecute/h1module/pool"
+	"github.com/X-ecute/h1module/store"
+)
 
 // PrintStructuredScopes displays structured scopes in a readable format
-func PrintStructuredScopes(scopes *StructuredScopesResponse, programHandle string) {
+func PrintStructuredScopes(scopes *client.StructuredScopesResponse, programHandle string) {
 	fmt.Printf("\n=== Structured Scopes for %s ===\n", programHandle)
 	fmt.Printf("Found %d scope entries\n\n", len(scopes.Data))
 
@@ -271,7 +38,7 @@ func PrintStructuredScopes(scopes *StructuredScopesResponse, programHandle strin
 }
 
 // PrintWeaknesses displays weaknesses in a readable format
-func PrintWeaknesses(weaknesses *WeaknessesResponse, programHandle string) {
+func PrintWeaknesses(weaknesses *client.WeaknessesResponse, programHandle string) {
 	fmt.Printf("\n=== Weaknesses for %s ===\n", programHandle)
 	fmt.Printf("Found %d weakness types\n\n", len(weaknesses.Data))
 
@@ -283,7 +50,7 @@ func PrintWeaknesses(weaknesses *WeaknessesResponse, programHandle string) {
 }
 
 // PrintPrograms displays programs in a readable format
-func PrintPrograms(programs []Program) {
+func PrintPrograms(programs []client.Program) {
 	fmt.Printf("\n=== All Programs ===\n")
 	fmt.Printf("Found %d programs\n\n", len(programs))
 
@@ -298,7 +65,7 @@ func PrintPrograms(programs []Program) {
 }
 
 // PrintProgram displays a single program in detail
-func PrintProgram(program *Program) {
+func PrintProgram(program *client.Program) {
 	fmt.Printf("\n=== Program Details ===\n")
 	fmt.Printf("Name: %s\n", program.Attributes.Name)
 	fmt.Printf("Handle: %s\n", program.Attributes.Handle)
@@ -311,6 +78,58 @@ func PrintProgram(program *Program) {
 	}
 }
 
+// extractGlobalFlag pulls "<flag> <value>" out of *args (wherever it
+// appears) and returns value, leaving the remaining arguments in place
+// for the mode-specific parsing in main to see. Returns "" if flag
+// isn't present.
+func extractGlobalFlag(args *[]string, flag string) string {
+	a := *args
+	for i, arg := range a {
+		if arg == flag && i+1 < len(a) {
+			value := a[i+1]
+			*args = append(append([]string{}, a[:i]...), a[i+2:]...)
+			return value
+		}
+	}
+	return ""
+}
+
+// extractConcurrencyFlag pulls "--concurrency <n>" out of args and
+// returns it as a pool.Options, or a zero-value Options (package
+// default) if the flag isn't present. A value that isn't a positive
+// integer is a fatal error.
+func extractConcurrencyFlag(args *[]string) pool.Options {
+	raw := extractGlobalFlag(args, "--concurrency")
+	if raw == "" {
+		return pool.Options{}
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n <= 0 {
+		log.Fatalf("--concurrency must be a positive integer, got %q", raw)
+	}
+	return pool.Options{Concurrency: n}
+}
+
+// applyCacheTTLFlags overrides opts.CacheTTL from "--cache-programs-ttl"
+// and "--cache-scope-ttl", each a duration string (e.g. "30m", "1h"),
+// leaving any TTL whose flag wasn't passed at its existing value.
+func applyCacheTTLFlags(args *[]string, opts *client.Options) {
+	if raw := extractGlobalFlag(args, "--cache-programs-ttl"); raw != "" {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			log.Fatalf("invalid --cache-programs-ttl %q: %v", raw, err)
+		}
+		opts.CacheTTL.ProgramsTTL = d
+	}
+	if raw := extractGlobalFlag(args, "--cache-scope-ttl"); raw != "" {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			log.Fatalf("invalid --cache-scope-ttl %q: %v", raw, err)
+		}
+		opts.CacheTTL.ScopeTTL = d
+	}
+}
+
 func main() {
 	// Get credentials from environment variables
 	username := os.Getenv("HACKERONE_USERNAME")
@@ -320,89 +139,297 @@ func main() {
 		log.Fatal("Please set HACKERONE_USERNAME and HACKERONE_TOKEN environment variables")
 	}
 
-	client := NewH1Client(username, token)
+	args := os.Args
+	opts := client.DefaultOptions()
+	cacheDir := extractGlobalFlag(&args, "--cache-dir")
+	cacheMemory := extractGlobalFlag(&args, "--cache-memory")
+	switch {
+	case cacheDir != "" && cacheMemory != "":
+		log.Fatal("--cache-dir and --cache-memory are mutually exclusive, pick one")
+	case cacheDir != "":
+		fileCache, err := cache.NewFileCache(cacheDir)
+		if err != nil {
+			log.Fatal(err)
+		}
+		opts.Cache = fileCache
+	case cacheMemory != "":
+		n, err := strconv.Atoi(cacheMemory)
+		if err != nil || n <= 0 {
+			log.Fatalf("--cache-memory must be a positive integer, got %q", cacheMemory)
+		}
+		opts.Cache = cache.NewMemoryCache(n)
+	}
+	applyCacheTTLFlags(&args, &opts)
+
+	h1 := client.NewWithOptions(username, token, opts)
+	ctx := context.Background()
 
-	if len(os.Args) < 2 {
-		fmt.Println("Usage:")
+	if len(args) < 2 {
+		fmt.Println("Usage (add --cache-dir <dir> or --cache-memory <n> (mutually exclusive), plus --cache-programs-ttl <dur>/--cache-scope-ttl <dur>, anywhere to cache GET responses with conditional requests):")
 		fmt.Println("  h1module scopes <program_handle>        - Get structured scopes for a program")
 		fmt.Println("  h1module weaknesses <program_handle>    - Get weaknesses for a program")
 		fmt.Println("  h1module programs                       - Get all programs (single page)")
 		fmt.Println("  h1module programs-all                   - Get ALL programs with pagination")
 		fmt.Println("  h1module programs-all-save <filename>   - Get ALL programs and save to file")
 		fmt.Println("  h1module program <program_handle>       - Get specific program details")
+		fmt.Println("  h1module crawl --resume <statefile> [--incremental]")
+		fmt.Println("                                           - Resumable crawl of ALL programs (with scopes+weaknesses), checkpointed to <statefile>")
+		fmt.Println("  h1module enrich-all <filename> [--concurrency <n>]")
+		fmt.Println("                                           - Fetch scopes+weaknesses for every program concurrently, save as JSON")
+		fmt.Println("  h1module scopes-all <filename> [--format json|domains|ndjson|csv|burp] [--concurrency <n>]")
+		fmt.Println("                                           - Fetch scopes for every program, save in a recon-tool-friendly format")
+		fmt.Println("  h1module diff <old.json> <new.json> [--format text|json|ndjson] [--webhook <url>]")
+		fmt.Println("                                           - Diff two saved snapshots and report added/removed/modified scope")
 		return
 	}
 
-	mode := os.Args[1]
+	mode := args[1]
 
 	switch strings.ToLower(mode) {
 	case "scopes":
-		if len(os.Args) < 3 {
+		if len(args) < 3 {
 			log.Fatal("Please provide a program handle")
 		}
-		programHandle := os.Args[2]
+		programHandle := args[2]
 
-		scopes, err := client.GetStructuredScopes(programHandle)
+		scopes, err := h1.GetStructuredScopes(ctx, programHandle)
 		if err != nil {
 			log.Fatalf("Error getting structured scopes: %v", err)
 		}
 		PrintStructuredScopes(scopes, programHandle)
 
 	case "weaknesses":
-		if len(os.Args) < 3 {
+		if len(args) < 3 {
 			log.Fatal("Please provide a program handle")
 		}
-		programHandle := os.Args[2]
+		programHandle := args[2]
 
-		weaknesses, err := client.GetWeaknesses(programHandle)
+		weaknesses, err := h1.GetWeaknesses(ctx, programHandle)
 		if err != nil {
 			log.Fatalf("Error getting weaknesses: %v", err)
 		}
 		PrintWeaknesses(weaknesses, programHandle)
 
 	case "programs":
-		programs, err := client.GetAllPrograms()
+		programs, err := h1.GetAllPrograms(ctx)
 		if err != nil {
 			log.Fatalf("Error getting programs: %v", err)
 		}
 		PrintPrograms(programs.Data)
 
 	case "programs-all":
-		programs, err := client.GetAllProgramsPaginated()
+		programs, err := h1.GetAllProgramsPaginated(ctx)
 		if err != nil {
 			log.Fatalf("Error getting all programs: %v", err)
 		}
 		PrintPrograms(programs)
 
 	case "programs-all-save":
-		if len(os.Args) < 3 {
+		if len(args) < 3 {
+			log.Fatal("Please provide a filename")
+		}
+		filename := args[2]
+		format, err := output.ParseFormatFlag(args[3:], output.FormatJSON)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		programs, err := h1.GetAllProgramsPaginated(ctx)
+		if err != nil {
+			log.Fatalf("Error getting all programs: %v", err)
+		}
+
+		if format == output.FormatNDJSON {
+			if err := output.WriteProgramsNDJSON(programs, filename); err != nil {
+				log.Fatalf("Error saving to file: %v", err)
+			}
+		} else {
+			if format != output.FormatJSON {
+				log.Fatalf("--format %s needs scope data; use 'scopes-all' instead", format)
+			}
+			if err := client.SaveProgramsToFile(programs, filename); err != nil {
+				log.Fatalf("Error saving to file: %v", err)
+			}
+		}
+
+	case "crawl":
+		rest := args[2:]
+		statePath := extractGlobalFlag(&rest, "--resume")
+		incremental := false
+		for _, a := range rest {
+			if a == "--incremental" {
+				incremental = true
+			}
+		}
+		if statePath == "" {
+			log.Fatal("Please provide a checkpoint file: h1module crawl --resume <statefile>")
+		}
+
+		crawler := store.NewCrawler(h1, statePath)
+
+		var previous []pool.Detail
+		if incremental {
+			if snapshot, ok, err := crawler.LoadPreviousSnapshot(); err != nil {
+				log.Fatalf("Error loading previous snapshot: %v", err)
+			} else if ok {
+				previous = snapshot
+			}
+		}
+
+		details, err := crawler.Run()
+		if err != nil {
+			log.Fatalf("Error crawling programs: %v", err)
+		}
+
+		programs := make([]client.Program, len(details))
+		for i, d := range details {
+			programs[i] = d.Program
+		}
+		PrintPrograms(programs)
+
+		if incremental {
+			entries := diff.ScopeDiff(previous, details)
+			diff.PrintScopeDiff(entries)
+		}
+
+	case "enrich-all":
+		if len(args) < 3 {
+			log.Fatal("Please provide a filename")
+		}
+		filename := args[2]
+		rest := args[3:]
+		enrichOpts := extractConcurrencyFlag(&rest)
+
+		programs, err := h1.GetAllProgramsPaginated(ctx)
+		if err != nil {
+			log.Fatalf("Error getting all programs: %v", err)
+		}
+
+		progressCh := make(chan pool.Progress)
+		go func() {
+			for p := range progressCh {
+				status := "ok"
+				if p.Err != nil {
+					status = "error"
+				}
+				fmt.Printf("[%d/%d] %s: %s\n", p.Done, p.Total, p.Handle, status)
+			}
+		}()
+
+		details, err := pool.EnrichPrograms(ctx, h1, programs, enrichOpts, progressCh)
+		close(progressCh)
+		if err != nil {
+			log.Fatalf("Error enriching programs: %v", err)
+		}
+
+		body, err := json.MarshalIndent(details, "", "  ")
+		if err != nil {
+			log.Fatalf("Error encoding enriched programs: %v", err)
+		}
+		if err := ioutil.WriteFile(filename, body, 0644); err != nil {
+			log.Fatalf("Error saving to file: %v", err)
+		}
+		fmt.Printf("Successfully saved %d enriched programs to %s\n", len(details), filename)
+
+	case "scopes-all":
+		if len(args) < 3 {
 			log.Fatal("Please provide a filename")
 		}
-		filename := os.Args[2]
+		filename := args[2]
+		rest := args[3:]
+		enrichOpts := extractConcurrencyFlag(&rest)
+		format, err := output.ParseFormatFlag(rest, output.FormatNDJSON)
+		if err != nil {
+			log.Fatal(err)
+		}
 
-		programs, err := client.GetAllProgramsPaginated()
+		programs, err := h1.GetAllProgramsPaginated(ctx)
 		if err != nil {
 			log.Fatalf("Error getting all programs: %v", err)
 		}
 
-		err = SaveProgramsToFile(programs, filename)
+		progressCh := make(chan pool.Progress)
+		go func() {
+			for p := range progressCh {
+				status := "ok"
+				if p.Err != nil {
+					status = "error"
+				}
+				fmt.Printf("[%d/%d] %s: %s\n", p.Done, p.Total, p.Handle, status)
+			}
+		}()
+
+		details, err := pool.EnrichScopes(ctx, h1, programs, enrichOpts, progressCh)
+		close(progressCh)
 		if err != nil {
+			log.Fatalf("Error fetching scopes: %v", err)
+		}
+
+		if err := output.WriteProgramDetails(details, filename, format); err != nil {
 			log.Fatalf("Error saving to file: %v", err)
 		}
 
+	case "diff":
+		if len(args) < 4 {
+			log.Fatal("Please provide two snapshot files: h1module diff <old.json> <new.json>")
+		}
+		oldPath, newPath := args[2], args[3]
+		rest := args[4:]
+
+		format := extractGlobalFlag(&rest, "--format")
+		if format == "" {
+			format = "text"
+		}
+		webhookURL := extractGlobalFlag(&rest, "--webhook")
+
+		previous, err := diff.LoadSnapshot(oldPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+		current, err := diff.LoadSnapshot(newPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		entries := diff.ScopeDiff(previous, current)
+
+		switch format {
+		case "text":
+			diff.PrintScopeDiff(entries)
+		case "ndjson":
+			for _, e := range entries {
+				body, _ := json.Marshal(e)
+				fmt.Println(string(body))
+			}
+		case "json":
+			body, err := json.MarshalIndent(entries, "", "  ")
+			if err != nil {
+				log.Fatal(err)
+			}
+			fmt.Println(string(body))
+		default:
+			log.Fatalf("Unknown --format %q (want text, json, or ndjson)", format)
+		}
+
+		if webhookURL != "" {
+			if err := diff.NotifyWebhook(webhookURL, entries); err != nil {
+				log.Fatalf("Error notifying webhook: %v", err)
+			}
+		}
+
 	case "program":
-		if len(os.Args) < 3 {
+		if len(args) < 3 {
 			log.Fatal("Please provide a program handle")
 		}
-		programHandle := os.Args[2]
+		programHandle := args[2]
 
-		program, err := client.GetProgram(programHandle)
+		program, err := h1.GetProgram(ctx, programHandle)
 		if err != nil {
 			log.Fatalf("Error getting program: %v", err)
 		}
 		PrintProgram(program)
 
 	default:
-		log.Fatal("Invalid mode. Use: scopes, weaknesses, programs, programs-all, programs-all-save, or program")
+		log.Fatal("Invalid mode. Use: scopes, weaknesses, programs, programs-all, programs-all-save, crawl, enrich-all, scopes-all, diff, or program")
 	}
-}
\ No newline at end of file
+}
@@ -0,0 +1,290 @@
+// Package output serializes enriched program/scope data into
+// recon-tool-friendly formats: JSON, NDJSON, CSV, a domains/wildcards
+// pair, and a Burp Suite target-scope document.
+package output
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/X-ecute/h1module/client"
+	"github.com/X-ecute/h1module/pool"
+)
+
+// Format selects how enriched program data is serialized for
+// downstream recon tooling.
+type Format string
+
+const (
+	// FormatJSON is a single indented JSON array (the original,
+	// default behavior).
+	FormatJSON Format = "json"
+	// FormatDomains splits in-scope URL/DOMAIN assets into
+	// domains.txt and wildcards.txt (entries starting with "*."),
+	// ready to pipe into subfinder/httpx.
+	FormatDomains Format = "domains"
+	// FormatNDJSON writes one JSON object per line.
+	FormatNDJSON Format = "ndjson"
+	// FormatCSV writes flattened program+scope rows.
+	FormatCSV Format = "csv"
+	// FormatBurp writes a Burp Suite target-scope JSON document.
+	FormatBurp Format = "burp"
+)
+
+// ParseFormat validates a --format flag value.
+func ParseFormat(s string) (Format, error) {
+	switch Format(strings.ToLower(s)) {
+	case FormatJSON, FormatDomains, FormatNDJSON, FormatCSV, FormatBurp:
+		return Format(strings.ToLower(s)), nil
+	default:
+		return "", fmt.Errorf("unknown format %q (want json, domains, ndjson, csv, or burp)", s)
+	}
+}
+
+const (
+	assetTypeURL    = "URL"
+	assetTypeDomain = "DOMAIN"
+)
+
+// inScopeAssets returns the structured scopes across details that are
+// submittable URL/DOMAIN assets - the set recon tooling cares about.
+// It trusts d.Scopes to already hold every page for the program (see
+// pool.EnrichScopes); it does no pagination of its own.
+func inScopeAssets(details []pool.Detail) []client.StructuredScope {
+	var assets []client.StructuredScope
+	for _, d := range details {
+		for _, s := range d.Scopes {
+			if !s.Attributes.EligibleForSubmission {
+				continue
+			}
+			if s.Attributes.AssetType != assetTypeURL && s.Attributes.AssetType != assetTypeDomain {
+				continue
+			}
+			assets = append(assets, s)
+		}
+	}
+	return assets
+}
+
+// WriteDomainsAndWildcards splits in-scope URL/DOMAIN assets into a
+// flat domains.txt and a wildcards.txt (entries starting with "*."),
+// written alongside basePath (its own name is ignored, only its
+// directory is used).
+func WriteDomainsAndWildcards(details []pool.Detail, basePath string) error {
+	dir := filepath.Dir(basePath)
+
+	var domains, wildcards []string
+	for _, s := range inScopeAssets(details) {
+		id := s.Attributes.AssetIdentifier
+		if strings.HasPrefix(id, "*.") {
+			wildcards = append(wildcards, id)
+		} else {
+			domains = append(domains, id)
+		}
+	}
+
+	domainsPath := filepath.Join(dir, "domains.txt")
+	if err := os.WriteFile(domainsPath, []byte(strings.Join(domains, "\n")+"\n"), 0644); err != nil {
+		return fmt.Errorf("error writing %s: %v", domainsPath, err)
+	}
+
+	wildcardsPath := filepath.Join(dir, "wildcards.txt")
+	if err := os.WriteFile(wildcardsPath, []byte(strings.Join(wildcards, "\n")+"\n"), 0644); err != nil {
+		return fmt.Errorf("error writing %s: %v", wildcardsPath, err)
+	}
+
+	fmt.Printf("Wrote %d domains to %s and %d wildcards to %s\n", len(domains), domainsPath, len(wildcards), wildcardsPath)
+	return nil
+}
+
+// WriteNDJSON writes one JSON-encoded item per line to path.
+func WriteNDJSON(details []pool.Detail, path string) error {
+	var b strings.Builder
+	for _, d := range details {
+		line, err := json.Marshal(d)
+		if err != nil {
+			return err
+		}
+		b.Write(line)
+		b.WriteByte('\n')
+	}
+	if err := os.WriteFile(path, []byte(b.String()), 0644); err != nil {
+		return fmt.Errorf("error writing %s: %v", path, err)
+	}
+	fmt.Printf("Wrote %d records to %s\n", len(details), path)
+	return nil
+}
+
+var csvHeader = []string{
+	"program_handle", "program_name", "program_state", "offers_bounties",
+	"asset_type", "asset_identifier", "eligible_for_bounty", "eligible_for_submission", "max_severity",
+}
+
+// WriteCSV writes one row per program+scope pair (a program with no
+// scopes gets a single row with the scope columns blank).
+func WriteCSV(details []pool.Detail, path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("error creating %s: %v", path, err)
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	if err := w.Write(csvHeader); err != nil {
+		return err
+	}
+
+	rows := 0
+	for _, d := range details {
+		p := d.Program.Attributes
+		if len(d.Scopes) == 0 {
+			if err := w.Write([]string{p.Handle, p.Name, p.State, strconv.FormatBool(p.OffersBounties), "", "", "", "", ""}); err != nil {
+				return err
+			}
+			rows++
+			continue
+		}
+		for _, s := range d.Scopes {
+			a := s.Attributes
+			row := []string{
+				p.Handle, p.Name, p.State, strconv.FormatBool(p.OffersBounties),
+				a.AssetType, a.AssetIdentifier, strconv.FormatBool(a.EligibleForBounty), strconv.FormatBool(a.EligibleForSubmission), a.MaxSeverity,
+			}
+			if err := w.Write(row); err != nil {
+				return err
+			}
+			rows++
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return err
+	}
+	fmt.Printf("Wrote %d rows to %s\n", rows, path)
+	return nil
+}
+
+// BurpScopeItem is a single include/exclude rule in a Burp Suite
+// target-scope document.
+type BurpScopeItem struct {
+	Enabled  bool   `json:"enabled"`
+	Host     string `json:"host"`
+	Protocol string `json:"protocol,omitempty"`
+}
+
+// BurpScope is the top-level shape Burp Suite expects from
+// Project options > Target Scope > Advanced > Paste URL list / JSON.
+type BurpScope struct {
+	Target struct {
+		Scope struct {
+			Include []BurpScopeItem `json:"include"`
+			Exclude []BurpScopeItem `json:"exclude"`
+		} `json:"scope"`
+	} `json:"target"`
+}
+
+// burpHostPattern converts a HackerOne asset identifier into the
+// regex-ish host pattern Burp's scope matcher expects, expanding a
+// leading "*." wildcard to "^.*\.example\.com$".
+func burpHostPattern(assetIdentifier string) string {
+	if strings.HasPrefix(assetIdentifier, "*.") {
+		suffix := strings.TrimPrefix(assetIdentifier, "*.")
+		return "^.*\\." + strings.ReplaceAll(suffix, ".", "\\.") + "$"
+	}
+	return "^" + strings.ReplaceAll(assetIdentifier, ".", "\\.") + "$"
+}
+
+// WriteBurpScope writes a Burp Suite target-scope JSON document derived
+// from in-scope URL/DOMAIN assets. Assets ineligible for submission are
+// emitted as exclude rules rather than dropped, so they show up in
+// Burp greyed out instead of silently disappearing.
+func WriteBurpScope(details []pool.Detail, path string) error {
+	var scope BurpScope
+	for _, d := range details {
+		for _, s := range d.Scopes {
+			if s.Attributes.AssetType != assetTypeURL && s.Attributes.AssetType != assetTypeDomain {
+				continue
+			}
+			item := BurpScopeItem{Enabled: true, Host: burpHostPattern(s.Attributes.AssetIdentifier)}
+			if s.Attributes.EligibleForSubmission {
+				scope.Target.Scope.Include = append(scope.Target.Scope.Include, item)
+			} else {
+				scope.Target.Scope.Exclude = append(scope.Target.Scope.Exclude, item)
+			}
+		}
+	}
+
+	body, err := json.MarshalIndent(scope, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, body, 0644); err != nil {
+		return fmt.Errorf("error writing %s: %v", path, err)
+	}
+	fmt.Printf("Wrote %d include and %d exclude rules to %s\n", len(scope.Target.Scope.Include), len(scope.Target.Scope.Exclude), path)
+	return nil
+}
+
+// ParseFormatFlag scans args for "--format <value>" and returns the
+// parsed Format, or def if no --format flag is present. An
+// unrecognized value is returned as an error, consistent with how the
+// CLI handles other malformed arguments.
+func ParseFormatFlag(args []string, def Format) (Format, error) {
+	for i, arg := range args {
+		if arg == "--format" && i+1 < len(args) {
+			return ParseFormat(args[i+1])
+		}
+	}
+	return def, nil
+}
+
+// WriteProgramsNDJSON writes one JSON-encoded program per line to path.
+func WriteProgramsNDJSON(programs []client.Program, path string) error {
+	var b strings.Builder
+	for _, p := range programs {
+		line, err := json.Marshal(p)
+		if err != nil {
+			return err
+		}
+		b.Write(line)
+		b.WriteByte('\n')
+	}
+	if err := os.WriteFile(path, []byte(b.String()), 0644); err != nil {
+		return fmt.Errorf("error writing %s: %v", path, err)
+	}
+	fmt.Printf("Wrote %d records to %s\n", len(programs), path)
+	return nil
+}
+
+// WriteProgramDetails writes details to path in the given format.
+// FormatDomains ignores path's base name (see WriteDomainsAndWildcards).
+func WriteProgramDetails(details []pool.Detail, path string, format Format) error {
+	switch format {
+	case FormatJSON, "":
+		body, err := json.MarshalIndent(details, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, body, 0644); err != nil {
+			return fmt.Errorf("error writing %s: %v", path, err)
+		}
+		fmt.Printf("Wrote %d records to %s\n", len(details), path)
+		return nil
+	case FormatDomains:
+		return WriteDomainsAndWildcards(details, path)
+	case FormatNDJSON:
+		return WriteNDJSON(details, path)
+	case FormatCSV:
+		return WriteCSV(details, path)
+	case FormatBurp:
+		return WriteBurpScope(details, path)
+	default:
+		return fmt.Errorf("unknown format %q", format)
+	}
+}
@@ -0,0 +1,142 @@
+package output
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/X-ecute/h1module/client"
+	"github.com/X-ecute/h1module/pool"
+)
+
+func scopeDetail(handle string, scopes ...client.StructuredScope) pool.Detail {
+	var p client.Program
+	p.Attributes.Handle = handle
+	return pool.Detail{Program: p, Scopes: scopes}
+}
+
+func submittableScope(assetType, identifier string) client.StructuredScope {
+	var s client.StructuredScope
+	s.Attributes.AssetType = assetType
+	s.Attributes.AssetIdentifier = identifier
+	s.Attributes.EligibleForSubmission = true
+	return s
+}
+
+func TestInScopeAssetsFiltersIneligibleAndNonHostTypes(t *testing.T) {
+	inScope := submittableScope(assetTypeDomain, "api.acme.com")
+
+	ineligible := submittableScope(assetTypeDomain, "legacy.acme.com")
+	ineligible.Attributes.EligibleForSubmission = false
+
+	otherType := submittableScope("SOURCE_CODE", "github.com/acme/repo")
+
+	details := []pool.Detail{scopeDetail("acme", inScope, ineligible, otherType)}
+
+	assets := inScopeAssets(details)
+	if len(assets) != 1 || assets[0].Attributes.AssetIdentifier != "api.acme.com" {
+		t.Fatalf("got %+v, want only the single eligible DOMAIN asset", assets)
+	}
+}
+
+func TestWriteDomainsAndWildcardsSplitsWildcards(t *testing.T) {
+	dir := t.TempDir()
+	details := []pool.Detail{scopeDetail("acme",
+		submittableScope(assetTypeDomain, "api.acme.com"),
+		submittableScope(assetTypeDomain, "*.acme.com"),
+	)}
+
+	if err := WriteDomainsAndWildcards(details, filepath.Join(dir, "out.json")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	domains, err := os.ReadFile(filepath.Join(dir, "domains.txt"))
+	if err != nil {
+		t.Fatalf("reading domains.txt: %v", err)
+	}
+	if strings.TrimSpace(string(domains)) != "api.acme.com" {
+		t.Fatalf("domains.txt = %q, want %q", domains, "api.acme.com\n")
+	}
+
+	wildcards, err := os.ReadFile(filepath.Join(dir, "wildcards.txt"))
+	if err != nil {
+		t.Fatalf("reading wildcards.txt: %v", err)
+	}
+	if strings.TrimSpace(string(wildcards)) != "*.acme.com" {
+		t.Fatalf("wildcards.txt = %q, want %q", wildcards, "*.acme.com\n")
+	}
+}
+
+func TestWriteCSVRowsIncludeScopelessPrograms(t *testing.T) {
+	dir := t.TempDir()
+	withScope := scopeDetail("acme", submittableScope(assetTypeURL, "https://api.acme.com"))
+	noScope := scopeDetail("other")
+	path := filepath.Join(dir, "out.csv")
+
+	if err := WriteCSV([]pool.Detail{withScope, noScope}, path); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	body, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading csv: %v", err)
+	}
+	lines := strings.Split(strings.TrimRight(string(body), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines (header + 2 rows), want 3:\n%s", len(lines), body)
+	}
+}
+
+func TestBurpHostPatternExpandsWildcards(t *testing.T) {
+	if got, want := burpHostPattern("*.acme.com"), `^.*\.acme\.com$`; got != want {
+		t.Fatalf("burpHostPattern(*.acme.com) = %q, want %q", got, want)
+	}
+	if got, want := burpHostPattern("api.acme.com"), `^api\.acme\.com$`; got != want {
+		t.Fatalf("burpHostPattern(api.acme.com) = %q, want %q", got, want)
+	}
+}
+
+func TestWriteBurpScopeSplitsIncludeExclude(t *testing.T) {
+	dir := t.TempDir()
+	included := submittableScope(assetTypeURL, "https://api.acme.com")
+	excluded := submittableScope(assetTypeURL, "https://staging.acme.com")
+	excluded.Attributes.EligibleForSubmission = false
+	path := filepath.Join(dir, "scope.json")
+
+	if err := WriteBurpScope([]pool.Detail{scopeDetail("acme", included, excluded)}, path); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	body, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading scope.json: %v", err)
+	}
+	if !strings.Contains(string(body), `"include"`) || !strings.Contains(string(body), `"exclude"`) {
+		t.Fatalf("scope.json missing include/exclude sections:\n%s", body)
+	}
+	var scope BurpScope
+	if err := json.Unmarshal(body, &scope); err != nil {
+		t.Fatalf("parsing scope.json: %v", err)
+	}
+	if len(scope.Target.Scope.Include) != 1 || len(scope.Target.Scope.Exclude) != 1 {
+		t.Fatalf("got %d include and %d exclude rules, want 1 and 1:\n%s", len(scope.Target.Scope.Include), len(scope.Target.Scope.Exclude), body)
+	}
+}
+
+func TestParseFormatFlagDefaultsWhenAbsent(t *testing.T) {
+	got, err := ParseFormatFlag([]string{"positional"}, FormatNDJSON)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != FormatNDJSON {
+		t.Fatalf("got %q, want default %q", got, FormatNDJSON)
+	}
+}
+
+func TestParseFormatFlagRejectsUnknownValue(t *testing.T) {
+	if _, err := ParseFormatFlag([]string{"--format", "yaml"}, FormatJSON); err == nil {
+		t.Fatal("expected an error for an unknown --format value")
+	}
+}
@@ -0,0 +1,189 @@
+// Package pool fans a list of programs out across a bounded set of
+// worker goroutines to fetch per-program detail (structured scopes and
+// weaknesses) concurrently, sharing the client's rate limiter.
+package pool
+
+import (
+	"context"
+	"sync"
+
+	"github.com/X-ecute/h1module/client"
+)
+
+// Detail is a Program merged with its structured scopes and
+// weaknesses, as produced by EnrichPrograms.
+type Detail struct {
+	Program    client.Program           `json:"program"`
+	Scopes     []client.StructuredScope `json:"scopes,omitempty"`
+	Weaknesses []client.Weakness        `json:"weaknesses,omitempty"`
+	Error      string                   `json:"error,omitempty"`
+}
+
+// Progress reports the completion of a single program during
+// EnrichPrograms, so callers can render a progress indicator without
+// waiting for the whole batch.
+type Progress struct {
+	Handle string
+	Done   int
+	Total  int
+	Err    error
+}
+
+// Options configures the worker pool behind EnrichPrograms.
+type Options struct {
+	// Concurrency is the number of worker goroutines fetching scopes
+	// and weaknesses in parallel. Defaults to 8 if zero or negative.
+	Concurrency int
+}
+
+const defaultConcurrency = 8
+
+// EnrichPrograms fans a list of programs out across a pool of worker
+// goroutines, each fetching structured scopes and weaknesses for one
+// program at a time via c. Workers share the client's rate limiter, so
+// concurrency controls how many requests are in flight, not how fast
+// they're issued. Results are returned in a slice in input order;
+// progress is streamed on progressCh (if non-nil) as each program
+// finishes, one Progress per program. progressCh is not closed by this
+// function - the caller owns it.
+//
+// Cancelling ctx stops workers from picking up new programs and causes
+// in-flight requests to fail fast; programs that didn't complete are
+// omitted from the returned slice (not zero-valued), with the context
+// error returned alongside whatever was collected.
+func EnrichPrograms(ctx context.Context, c *client.H1Client, programs []client.Program, opts Options, progressCh chan<- Progress) ([]Detail, error) {
+	return fanOut(ctx, programs, opts, progressCh, func(ctx context.Context, p client.Program) Detail {
+		return enrichOne(ctx, c, p)
+	})
+}
+
+// EnrichScopes is like EnrichPrograms but only fetches structured
+// scopes, skipping weaknesses - half the requests for callers (e.g. the
+// scopes-all recon-output modes) that don't need CWE data.
+func EnrichScopes(ctx context.Context, c *client.H1Client, programs []client.Program, opts Options, progressCh chan<- Progress) ([]Detail, error) {
+	return fanOut(ctx, programs, opts, progressCh, func(ctx context.Context, p client.Program) Detail {
+		return enrichScopesOnly(ctx, c, p)
+	})
+}
+
+// fanOut runs fetch across programs using a pool of opts.Concurrency
+// worker goroutines, all sharing the client's rate limiter. See
+// EnrichPrograms for the progressCh and cancellation contract.
+func fanOut(ctx context.Context, programs []client.Program, opts Options, progressCh chan<- Progress, fetch func(context.Context, client.Program) Detail) ([]Detail, error) {
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultConcurrency
+	}
+
+	type job struct {
+		index   int
+		program client.Program
+	}
+	type result struct {
+		index  int
+		detail Detail
+	}
+
+	jobs := make(chan job)
+	results := make(chan result, len(programs))
+
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				detail := fetch(ctx, j.program)
+				results <- result{index: j.index, detail: detail}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		for i, p := range programs {
+			select {
+			case jobs <- job{index: i, program: p}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	details := make([]Detail, len(programs))
+	completed := make([]bool, len(programs))
+	done := 0
+
+	for r := range results {
+		details[r.index] = r.detail
+		completed[r.index] = true
+		done++
+
+		if progressCh != nil {
+			var err error
+			if r.detail.Error != "" {
+				err = &client.RequestError{Endpoint: r.detail.Program.Attributes.Handle}
+			}
+			select {
+			case progressCh <- Progress{Handle: r.detail.Program.Attributes.Handle, Done: done, Total: len(programs), Err: err}:
+			case <-ctx.Done():
+			}
+		}
+	}
+
+	out := make([]Detail, 0, done)
+	for i, ok := range completed {
+		if ok {
+			out = append(out, details[i])
+		}
+	}
+
+	if ctx.Err() != nil {
+		return out, ctx.Err()
+	}
+	return out, nil
+}
+
+// enrichOne fetches every page of scopes and weaknesses for a single
+// program. A fetch failure is recorded on the returned Detail rather
+// than aborting the pool, so one bad program doesn't sink the whole
+// batch.
+func enrichOne(ctx context.Context, c *client.H1Client, program client.Program) Detail {
+	detail := Detail{Program: program}
+
+	scopes, err := c.GetStructuredScopesPaginated(ctx, program.Attributes.Handle)
+	if err != nil {
+		detail.Error = err.Error()
+		return detail
+	}
+	detail.Scopes = scopes
+
+	weaknesses, err := c.GetWeaknessesPaginated(ctx, program.Attributes.Handle)
+	if err != nil {
+		detail.Error = err.Error()
+		return detail
+	}
+	detail.Weaknesses = weaknesses
+
+	return detail
+}
+
+// enrichScopesOnly fetches every page of structured scopes for a
+// single program, leaving Weaknesses empty.
+func enrichScopesOnly(ctx context.Context, c *client.H1Client, program client.Program) Detail {
+	detail := Detail{Program: program}
+
+	scopes, err := c.GetStructuredScopesPaginated(ctx, program.Attributes.Handle)
+	if err != nil {
+		detail.Error = err.Error()
+		return detail
+	}
+	detail.Scopes = scopes
+
+	return detail
+}
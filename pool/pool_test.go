@@ -0,0 +1,97 @@
+package pool
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/X-ecute/h1module/client"
+)
+
+func programs(n int) []client.Program {
+	out := make([]client.Program, n)
+	for i := range out {
+		out[i].Attributes.Handle = fmt.Sprintf("program-%d", i)
+	}
+	return out
+}
+
+func TestFanOutPreservesOrder(t *testing.T) {
+	ps := programs(10)
+
+	fetch := func(ctx context.Context, p client.Program) Detail {
+		return Detail{Program: p}
+	}
+
+	details, err := fanOut(context.Background(), ps, Options{Concurrency: 3}, nil, fetch)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(details) != len(ps) {
+		t.Fatalf("got %d details, want %d", len(details), len(ps))
+	}
+	for i, d := range details {
+		if d.Program.Attributes.Handle != ps[i].Attributes.Handle {
+			t.Fatalf("details[%d] = %s, want %s (results out of input order)", i, d.Program.Attributes.Handle, ps[i].Attributes.Handle)
+		}
+	}
+}
+
+func TestFanOutCancellationStopsEarly(t *testing.T) {
+	ps := programs(20)
+
+	var started int32
+	fetch := func(ctx context.Context, p client.Program) Detail {
+		atomic.AddInt32(&started, 1)
+		select {
+		case <-time.After(50 * time.Millisecond):
+		case <-ctx.Done():
+		}
+		return Detail{Program: p}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+
+	details, err := fanOut(ctx, ps, Options{Concurrency: 4}, nil, fetch)
+	if err == nil {
+		t.Fatal("expected a context error after cancellation, got nil")
+	}
+	if len(details) >= len(ps) {
+		t.Fatalf("got %d completed details, want fewer than all %d (cancellation should stop the pool early)", len(details), len(ps))
+	}
+}
+
+func TestFanOutReportsProgress(t *testing.T) {
+	ps := programs(5)
+
+	fetch := func(ctx context.Context, p client.Program) Detail {
+		return Detail{Program: p}
+	}
+
+	progressCh := make(chan Progress, len(ps))
+	details, err := fanOut(context.Background(), ps, Options{Concurrency: 2}, progressCh, fetch)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	close(progressCh)
+
+	seen := 0
+	for p := range progressCh {
+		seen++
+		if p.Total != len(ps) {
+			t.Fatalf("progress.Total = %d, want %d", p.Total, len(ps))
+		}
+	}
+	if seen != len(ps) {
+		t.Fatalf("got %d progress updates, want %d", seen, len(ps))
+	}
+	if len(details) != len(ps) {
+		t.Fatalf("got %d details, want %d", len(details), len(ps))
+	}
+}
@@ -0,0 +1,115 @@
+// Package ratelimit provides a shared token-bucket limiter and a capped
+// exponential-backoff retry policy, used by the client package to stay
+// under HackerOne's request-rate limit and to recover from transient
+// 429/503/5xx responses.
+package ratelimit
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Limiter is a minimal goroutine-safe token-bucket rate limiter, sized
+// for HackerOne's 600 requests/minute API limit. It's shared across
+// every client call so concurrent callers (see the pool package) draw
+// from the same budget.
+type Limiter struct {
+	mu         sync.Mutex
+	tokens     float64
+	max        float64
+	refillRate float64 // tokens per second
+	last       time.Time
+}
+
+// NewLimiter creates a bucket that refills at qps tokens/second up to a
+// maximum of burst tokens.
+func NewLimiter(qps float64, burst int) *Limiter {
+	return &Limiter{
+		tokens:     float64(burst),
+		max:        float64(burst),
+		refillRate: qps,
+		last:       time.Now(),
+	}
+}
+
+// Wait blocks until a token is available or ctx is cancelled.
+func (l *Limiter) Wait(ctx context.Context) error {
+	for {
+		l.mu.Lock()
+		now := time.Now()
+		elapsed := now.Sub(l.last).Seconds()
+		l.tokens = minFloat(l.max, l.tokens+elapsed*l.refillRate)
+		l.last = now
+
+		if l.tokens >= 1 {
+			l.tokens--
+			l.mu.Unlock()
+			return nil
+		}
+
+		wait := time.Duration((1 - l.tokens) / l.refillRate * float64(time.Second))
+		l.mu.Unlock()
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func minFloat(a, b float64) float64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// RetryPolicy controls how a client retries rate-limited and
+// transient-5xx responses.
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+// DefaultRetryPolicy is a capped exponential backoff with jitter: up to
+// 5 attempts, starting at 500ms and never waiting more than 30s.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 5,
+	BaseDelay:   500 * time.Millisecond,
+	MaxDelay:    30 * time.Second,
+}
+
+// Backoff returns the delay before retry attempt n (1-indexed), with
+// full jitter applied.
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	d := p.BaseDelay * time.Duration(1<<uint(attempt-1))
+	if d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	return time.Duration(rand.Int63n(int64(d) + 1))
+}
+
+// ParseRetryAfter reads a Retry-After header, which HackerOne sends as
+// either an integer number of seconds or an HTTP-date.
+func ParseRetryAfter(header string) time.Duration {
+	if header == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(header); err == nil {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(header); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
@@ -0,0 +1,86 @@
+package ratelimit
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestLimiterAllowsBurst(t *testing.T) {
+	l := NewLimiter(1, 3)
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := l.Wait(ctx); err != nil {
+			t.Fatalf("Wait() #%d returned error: %v", i, err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Fatalf("burst of 3 took %v, want near-instant", elapsed)
+	}
+}
+
+func TestLimiterThrottlesPastBurst(t *testing.T) {
+	l := NewLimiter(20, 1) // 1 token burst, refills at 20/s (50ms each)
+	ctx := context.Background()
+
+	if err := l.Wait(ctx); err != nil {
+		t.Fatalf("first Wait() returned error: %v", err)
+	}
+
+	start := time.Now()
+	if err := l.Wait(ctx); err != nil {
+		t.Fatalf("second Wait() returned error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 25*time.Millisecond {
+		t.Fatalf("second Wait() returned after %v, want it to block for a refill", elapsed)
+	}
+}
+
+func TestLimiterRespectsCancellation(t *testing.T) {
+	l := NewLimiter(0.001, 1) // effectively never refills within the test
+	ctx := context.Background()
+	if err := l.Wait(ctx); err != nil {
+		t.Fatalf("first Wait() returned error: %v", err)
+	}
+
+	cancelCtx, cancel := context.WithCancel(ctx)
+	cancel()
+
+	if err := l.Wait(cancelCtx); err == nil {
+		t.Fatal("Wait() on a cancelled context should return an error")
+	}
+}
+
+func TestRetryPolicyBackoffIsCapped(t *testing.T) {
+	p := RetryPolicy{MaxAttempts: 10, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond}
+
+	for attempt := 1; attempt <= 10; attempt++ {
+		if d := p.Backoff(attempt); d > p.MaxDelay {
+			t.Fatalf("Backoff(%d) = %v, want <= MaxDelay %v", attempt, d, p.MaxDelay)
+		}
+	}
+}
+
+func TestParseRetryAfterSeconds(t *testing.T) {
+	d := ParseRetryAfter("30")
+	if d != 30*time.Second {
+		t.Fatalf("ParseRetryAfter(\"30\") = %v, want 30s", d)
+	}
+}
+
+func TestParseRetryAfterHTTPDate(t *testing.T) {
+	future := time.Now().Add(1 * time.Hour).UTC().Format(http.TimeFormat)
+	d := ParseRetryAfter(future)
+	if d <= 0 || d > time.Hour {
+		t.Fatalf("ParseRetryAfter(%q) = %v, want a positive duration close to 1h", future, d)
+	}
+}
+
+func TestParseRetryAfterEmpty(t *testing.T) {
+	if d := ParseRetryAfter(""); d != 0 {
+		t.Fatalf("ParseRetryAfter(\"\") = %v, want 0", d)
+	}
+}
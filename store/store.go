@@ -0,0 +1,208 @@
+// Package store drives a resumable, checkpointed crawl of every
+// HackerOne program (plus each program's structured scopes and
+// weaknesses), persisting progress to disk so a long crawl can be
+// interrupted and resumed without refetching everything from page 1.
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/X-ecute/h1module/client"
+	"github.com/X-ecute/h1module/pool"
+)
+
+// CrawlState is the on-disk checkpoint for a resumable programs crawl.
+// It is written after every page so a crawl can be interrupted
+// (Ctrl-C, crash, rate-limit ban) and picked back up with --resume
+// instead of refetching everything from page 1. Once Done, it also
+// doubles as the full snapshot consumed by --incremental diffing.
+//
+// Earlier revisions also tracked per-program ETags here, but that's
+// redundant now that the client package's conditional-GET cache already
+// handles ETag/If-None-Match transparently at the HTTP layer - there's
+// nothing left for the crawl state to do with them.
+type CrawlState struct {
+	NextURL   string               `json:"next_url"`
+	Page      int                  `json:"page"`
+	Programs  []pool.Detail        `json:"programs"`
+	LastSeen  map[string]time.Time `json:"last_seen,omitempty"`
+	UpdatedAt time.Time            `json:"updated_at"`
+	Done      bool                 `json:"done"`
+}
+
+// LoadCrawlState reads a CrawlState from statePath. A missing file is not
+// an error; it just means this is a fresh crawl, and a fresh state
+// starting at "/programs", page 1, is returned.
+func LoadCrawlState(statePath string) (*CrawlState, error) {
+	body, err := ioutil.ReadFile(statePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &CrawlState{NextURL: "/programs", Page: 1, LastSeen: map[string]time.Time{}}, nil
+		}
+		return nil, fmt.Errorf("error reading state file %s: %v", statePath, err)
+	}
+
+	var state CrawlState
+	if err := json.Unmarshal(body, &state); err != nil {
+		return nil, fmt.Errorf("error parsing state file %s: %v", statePath, err)
+	}
+	if state.LastSeen == nil {
+		state.LastSeen = map[string]time.Time{}
+	}
+	return &state, nil
+}
+
+// SaveCrawlState writes state to statePath atomically (write to a temp
+// file in the same directory, then rename) so a crash mid-write never
+// leaves a corrupt checkpoint behind.
+func SaveCrawlState(statePath string, state *CrawlState) error {
+	state.UpdatedAt = time.Now()
+
+	body, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmpPath := statePath + ".tmp"
+	if err := ioutil.WriteFile(tmpPath, body, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, statePath)
+}
+
+// Crawler drives a resumable crawl of every program's details,
+// flushing a CrawlState checkpoint to StatePath after every page.
+type Crawler struct {
+	Client    *client.H1Client
+	StatePath string
+}
+
+// NewCrawler creates a Crawler that checkpoints to statePath.
+func NewCrawler(c *client.H1Client, statePath string) *Crawler {
+	return &Crawler{Client: c, StatePath: statePath}
+}
+
+// LoadPreviousSnapshot reads a completed crawl from cr.StatePath, for
+// callers doing an --incremental diff against a prior run. ok is false
+// if there's no checkpoint yet, or the checkpoint is from a crawl that
+// never finished.
+func (cr *Crawler) LoadPreviousSnapshot() (snapshot []pool.Detail, ok bool, err error) {
+	state, err := LoadCrawlState(cr.StatePath)
+	if err != nil {
+		return nil, false, err
+	}
+	if !state.Done {
+		return nil, false, nil
+	}
+	return state.Programs, true, nil
+}
+
+// Run executes the crawl, resuming from StatePath if a checkpoint
+// exists there. For every page of the programs list it also fetches
+// each program's structured scopes and weaknesses (paginated, same as
+// the top-level programs list), recording LastSeen for every program it
+// confirms still exists. It installs a SIGINT/SIGTERM handler so Ctrl-C
+// flushes the current state before exiting instead of losing the
+// in-flight page. A checkpoint from a finished crawl is not resumed: Run
+// starts a new crawl from page 1 over it, keeping LastSeen. On
+// completion it returns the final program details.
+func (cr *Crawler) Run() ([]pool.Detail, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
+
+	state, err := LoadCrawlState(cr.StatePath)
+	if err != nil {
+		return nil, err
+	}
+	if state.Done {
+		state = &CrawlState{NextURL: "/programs", Page: 1, LastSeen: state.LastSeen}
+	}
+	if state.Page > 1 || len(state.Programs) > 0 {
+		fmt.Printf("Resuming crawl from %s (page %d, %d programs so far)\n", cr.StatePath, state.Page, len(state.Programs))
+	}
+
+	go func() {
+		select {
+		case <-sigCh:
+			fmt.Println("\nInterrupted, flushing checkpoint...")
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	for !state.Done {
+		select {
+		case <-ctx.Done():
+			if err := SaveCrawlState(cr.StatePath, state); err != nil {
+				return nil, fmt.Errorf("error flushing checkpoint after cancellation: %v", err)
+			}
+			return state.Programs, fmt.Errorf("crawl cancelled, resume with --resume %s", cr.StatePath)
+		default:
+		}
+
+		fmt.Printf("Fetching page %d...\n", state.Page)
+		response, next, err := cr.Client.GetProgramsPage(ctx, state.NextURL)
+		if err != nil {
+			// Flush what we have so the next run can resume from here.
+			if saveErr := SaveCrawlState(cr.StatePath, state); saveErr != nil {
+				return nil, fmt.Errorf("error fetching page %d: %v (checkpoint flush also failed: %v)", state.Page, err, saveErr)
+			}
+			return nil, fmt.Errorf("error fetching page %d: %v (checkpoint saved, resume with --resume %s)", state.Page, err, cr.StatePath)
+		}
+
+		for _, p := range response.Data {
+			detail := cr.fetchDetail(ctx, p)
+			state.Programs = append(state.Programs, detail)
+			state.LastSeen[p.Attributes.Handle] = time.Now()
+		}
+		fmt.Printf("Page %d: fetched %d programs (total: %d)\n", state.Page, len(response.Data), len(state.Programs))
+
+		if next != "" {
+			state.NextURL = next
+			state.Page++
+		} else {
+			state.Done = true
+		}
+
+		if err := SaveCrawlState(cr.StatePath, state); err != nil {
+			return nil, fmt.Errorf("error saving checkpoint at page %d: %v", state.Page, err)
+		}
+	}
+
+	return state.Programs, nil
+}
+
+// fetchDetail fetches the full paginated structured-scope and weakness
+// set for a single program. A fetch failure is recorded on the
+// returned Detail rather than aborting the crawl, so one bad program
+// doesn't sink an otherwise-successful checkpoint.
+func (cr *Crawler) fetchDetail(ctx context.Context, p client.Program) pool.Detail {
+	detail := pool.Detail{Program: p}
+
+	scopes, err := cr.Client.GetStructuredScopesPaginated(ctx, p.Attributes.Handle)
+	if err != nil {
+		detail.Error = err.Error()
+		return detail
+	}
+	detail.Scopes = scopes
+
+	weaknesses, err := cr.Client.GetWeaknessesPaginated(ctx, p.Attributes.Handle)
+	if err != nil {
+		detail.Error = err.Error()
+		return detail
+	}
+	detail.Weaknesses = weaknesses
+
+	return detail
+}
@@ -0,0 +1,153 @@
+package store
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/X-ecute/h1module/client"
+	"github.com/X-ecute/h1module/pool"
+)
+
+// pagedProgramsServer serves a two-page /programs list (one program per
+// page) plus empty structured_scopes/weaknesses for every program, using
+// the real HackerOne base URL in its "next" links so extractEndpoint's
+// prefix-stripping behaves the same way it does against the real API.
+func pagedProgramsServer() *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/programs", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.RawQuery == "page=2" {
+			fmt.Fprint(w, `{"data":[{"id":"2","type":"program","attributes":{"handle":"bravo"}}],"links":{}}`)
+			return
+		}
+		fmt.Fprint(w, `{"data":[{"id":"1","type":"program","attributes":{"handle":"alpha"}}],"links":{"next":"https://api.hackerone.com/v1/hackers/programs?page=2"}}`)
+	})
+	mux.HandleFunc("/programs/alpha/structured_scopes", emptyList)
+	mux.HandleFunc("/programs/bravo/structured_scopes", emptyList)
+	mux.HandleFunc("/programs/alpha/weaknesses", emptyList)
+	mux.HandleFunc("/programs/bravo/weaknesses", emptyList)
+	return httptest.NewServer(mux)
+}
+
+func emptyList(w http.ResponseWriter, r *http.Request) {
+	fmt.Fprint(w, `{"data":[],"links":{}}`)
+}
+
+func newTestClient(baseURL string) *client.H1Client {
+	c := client.New("user", "token")
+	c.BaseURL = baseURL
+	return c
+}
+
+func TestCrawlerRunFetchesAllPagesToCompletion(t *testing.T) {
+	server := pagedProgramsServer()
+	defer server.Close()
+
+	statePath := filepath.Join(t.TempDir(), "state.json")
+	cr := NewCrawler(newTestClient(server.URL), statePath)
+
+	details, err := cr.Run()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(details) != 2 {
+		t.Fatalf("got %d programs, want 2 (one per page)", len(details))
+	}
+
+	state, err := LoadCrawlState(statePath)
+	if err != nil {
+		t.Fatalf("LoadCrawlState: %v", err)
+	}
+	if !state.Done {
+		t.Fatal("state.Done = false after a full crawl")
+	}
+	if len(state.LastSeen) != 2 {
+		t.Fatalf("got %d LastSeen entries, want 2", len(state.LastSeen))
+	}
+}
+
+func TestCrawlerRunResumesFromCheckpoint(t *testing.T) {
+	server := pagedProgramsServer()
+	defer server.Close()
+
+	statePath := filepath.Join(t.TempDir(), "state.json")
+
+	// Simulate a crawl that was interrupted right after page 1: the
+	// checkpoint already has alpha's detail, and NextURL points at page 2.
+	var alpha client.Program
+	alpha.Attributes.Handle = "alpha"
+	precrawled := &CrawlState{
+		NextURL:  "/programs?page=2",
+		Page:     2,
+		Programs: []pool.Detail{{Program: alpha}},
+		LastSeen: map[string]time.Time{"alpha": time.Now()},
+	}
+	if err := SaveCrawlState(statePath, precrawled); err != nil {
+		t.Fatalf("seeding checkpoint: %v", err)
+	}
+
+	cr := NewCrawler(newTestClient(server.URL), statePath)
+	details, err := cr.Run()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(details) != 2 {
+		t.Fatalf("got %d programs after resume, want 2 (1 already checkpointed + 1 fetched)", len(details))
+	}
+
+	handles := map[string]bool{}
+	for _, d := range details {
+		handles[d.Program.Attributes.Handle] = true
+	}
+	if !handles["alpha"] || !handles["bravo"] {
+		t.Fatalf("got handles %v, want both alpha (from checkpoint) and bravo (fetched on resume)", handles)
+	}
+
+	state, err := LoadCrawlState(statePath)
+	if err != nil {
+		t.Fatalf("LoadCrawlState: %v", err)
+	}
+	if !state.Done {
+		t.Fatal("state.Done = false after resuming to completion")
+	}
+}
+
+func TestLoadCrawlStateMissingFileStartsFresh(t *testing.T) {
+	state, err := LoadCrawlState(filepath.Join(t.TempDir(), "does-not-exist.json"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if state.NextURL != "/programs" || state.Page != 1 || state.Done {
+		t.Fatalf("got %+v, want a fresh state starting at page 1", state)
+	}
+}
+
+func TestLoadPreviousSnapshotRequiresDoneState(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), "state.json")
+	var p client.Program
+	p.Attributes.Handle = "acme"
+
+	if err := SaveCrawlState(statePath, &CrawlState{Programs: []pool.Detail{{Program: p}}, Done: false}); err != nil {
+		t.Fatalf("seeding checkpoint: %v", err)
+	}
+	cr := NewCrawler(newTestClient("http://unused.invalid"), statePath)
+	if _, ok, err := cr.LoadPreviousSnapshot(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if ok {
+		t.Fatal("LoadPreviousSnapshot reported ok=true for a checkpoint that never finished")
+	}
+
+	if err := SaveCrawlState(statePath, &CrawlState{Programs: []pool.Detail{{Program: p}}, Done: true}); err != nil {
+		t.Fatalf("seeding completed checkpoint: %v", err)
+	}
+	snapshot, ok, err := cr.LoadPreviousSnapshot()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !ok || len(snapshot) != 1 || snapshot[0].Program.Attributes.Handle != "acme" {
+		t.Fatalf("got %+v, %v, want the completed snapshot's single program", snapshot, ok)
+	}
+}